@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"math"
 	"net/http"
@@ -16,11 +17,13 @@ import (
 	"github.com/beatkind/watchtower/internal/meta"
 	"github.com/beatkind/watchtower/pkg/api"
 	apiMetrics "github.com/beatkind/watchtower/pkg/api/metrics"
-	"github.com/beatkind/watchtower/pkg/api/update"
+	"github.com/beatkind/watchtower/pkg/cluster"
 	"github.com/beatkind/watchtower/pkg/container"
 	"github.com/beatkind/watchtower/pkg/filters"
 	"github.com/beatkind/watchtower/pkg/metrics"
 	"github.com/beatkind/watchtower/pkg/notifications"
+	"github.com/beatkind/watchtower/pkg/registry/throttle"
+	"github.com/beatkind/watchtower/pkg/strategy"
 	t "github.com/beatkind/watchtower/pkg/types"
 	"github.com/robfig/cron"
 	log "github.com/sirupsen/logrus"
@@ -43,6 +46,23 @@ var (
 	rollingRestart    bool
 	scope             string
 	labelPrecedence   bool
+	updateStrategy    string
+	canaryPercentage  int
+	stageLabel        string
+	stageWait         time.Duration
+	rolloutStrategy   strategy.Strategy
+	clusterBackend    string
+	clusterID         string
+	coordinator       *cluster.Coordinator
+	registryRate      float64
+	registryBurst     int
+	headCacheTTL      time.Duration
+	registryConfig    string
+	registryGate      *throttle.Gate
+	httpAPIListen     string
+	httpAPITLSCert    string
+	httpAPITLSKey     string
+	httpAPIClientCA   string
 )
 
 var rootCmd = NewRootCommand()
@@ -67,6 +87,51 @@ func init() {
 	flags.RegisterDockerFlags(rootCmd)
 	flags.RegisterSystemFlags(rootCmd)
 	flags.RegisterNotificationFlags(rootCmd)
+	registerStrategyFlags(rootCmd)
+	registerClusterFlags(rootCmd)
+	registerRegistryThrottleFlags(rootCmd)
+	registerAPIFlags(rootCmd)
+}
+
+// registerAPIFlags adds the flags controlling the HTTP API's listen address
+// and TLS/mTLS configuration.
+func registerAPIFlags(cmd *cobra.Command) {
+	f := cmd.PersistentFlags()
+	f.StringVar(&httpAPIListen, "http-api-listen", api.DefaultAddr, "Address the HTTP API listens on")
+	f.StringVar(&httpAPITLSCert, "http-api-tls-cert", "", "Path to a PEM certificate used to serve the HTTP API over TLS")
+	f.StringVar(&httpAPITLSKey, "http-api-tls-key", "", "Path to the PEM private key matching --http-api-tls-cert")
+	f.StringVar(&httpAPIClientCA, "http-api-client-ca", "", "Path to a PEM CA bundle used to require and verify client certificates (mTLS)")
+}
+
+// registerStrategyFlags adds the flags controlling which strategy.Strategy
+// is used to roll out an update, on top of the existing all-at-once and
+// rolling-restart modes.
+func registerStrategyFlags(cmd *cobra.Command) {
+	f := cmd.PersistentFlags()
+	f.StringVar(&updateStrategy, "update-strategy", "", "Update rollout strategy: all-at-once (default), canary, blue-green or staged")
+	f.IntVar(&canaryPercentage, "canary-percentage", 10, "Percentage of matching containers updated in the first canary batch")
+	f.StringVar(&stageLabel, "stage-label", "", "Container label used to group containers into stages for the staged strategy")
+	f.DurationVar(&stageWait, "stage-wait", 0, "How long to wait after a stage completes before starting the next one")
+}
+
+// registerClusterFlags adds the flags controlling cross-host leader
+// election, used so that only one watchtower instance in a fleet pulls
+// images and performs updates at a time.
+func registerClusterFlags(cmd *cobra.Command) {
+	f := cmd.PersistentFlags()
+	f.StringVar(&clusterBackend, "cluster-backend", "", "Cluster coordination backend: registry (default), redis, consul or etcd")
+	f.StringVar(&clusterID, "cluster-id", "", "Cluster ID shared by every watchtower instance that should coordinate updates; leader election is disabled when empty")
+}
+
+// registerRegistryThrottleFlags adds the flags controlling the per-registry
+// rate limit and digest cache applied before a HEAD/GET request reaches a
+// registry.
+func registerRegistryThrottleFlags(cmd *cobra.Command) {
+	f := cmd.PersistentFlags()
+	f.Float64Var(&registryRate, "registry-rate", 0, "Requests per second allowed against a single registry (0 disables rate limiting)")
+	f.IntVar(&registryBurst, "registry-burst", 1, "Maximum number of requests allowed back to back against a single registry before --registry-rate applies")
+	f.DurationVar(&headCacheTTL, "head-cache-ttl", 0, "How long a digest check is cached per registry+repo+tag before it's repeated")
+	f.StringVar(&registryConfig, "registry-config", "", "Path to a JSON file with per-registry overrides for --registry-rate, --registry-burst and --head-cache-ttl")
 }
 
 // Execute the root func and exit in case of errors
@@ -134,6 +199,47 @@ func PreRun(cmd *cobra.Command, _ []string) {
 
 	notifier = notifications.NewNotifier(cmd)
 	notifier.AddLogHook()
+
+	var strategyErr error
+	rolloutStrategy, strategyErr = strategy.New(updateStrategy, strategy.Options{
+		CanaryPercentage: canaryPercentage,
+		StageLabel:       stageLabel,
+		StageWait:        stageWait,
+	})
+	if strategyErr != nil {
+		log.Fatal(strategyErr)
+	}
+
+	if clusterID != "" {
+		coordinator, err = cluster.New(cluster.Config{Backend: clusterBackend, ID: clusterID}, clusterHolderID())
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	throttleConfig, err := throttle.LoadConfig(registryConfig, throttle.Limits{
+		RateLimit: registryRate,
+		Burst:     registryBurst,
+		CacheTTL:  headCacheTTL,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	// registryGate is consulted by actions.Update and buildContainersLister
+	// before each container's staleness check, so repeated scans of the
+	// same image back off behind a single per-registry rate limit and
+	// cache.
+	registryGate = throttle.NewGate(throttleConfig)
+}
+
+// clusterHolderID identifies this process to other watchtower instances
+// contending for the same cluster.Config.ID.
+func clusterHolderID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown-host"
+	}
+	return host + "-" + strconv.Itoa(os.Getpid())
 }
 
 // Run is the main execution flow of the command
@@ -181,14 +287,35 @@ func Run(c *cobra.Command, names []string) {
 	updateLock := make(chan bool, 1)
 	updateLock <- true
 
-	httpAPI := api.New(apiToken)
+	httpAPI, err := api.NewWithOptions(apiToken, api.Options{
+		Addr:         httpAPIListen,
+		TLSCertFile:  httpAPITLSCert,
+		TLSKeyFile:   httpAPITLSKey,
+		ClientCAFile: httpAPIClientCA,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	if enableUpdateAPI {
-		updateHandler := update.New(func(images []string) {
-			metric := runUpdatesWithNotifications(filters.FilterByImage(images, filter))
-			metrics.RegisterScan(metric)
-		}, updateLock)
-		httpAPI.RegisterFunc(updateHandler.Path, updateHandler.Handle)
+		// The job-based handlers below replace the legacy image-triggered
+		// update.New handler at /v1/update. Job polling via /v1/jobs/{id}
+		// supersedes its callback-only behaviour.
+		jobStore := api.NewJobStore()
+		runner := buildRunner(filter, updateLock)
+
+		jobsHandler := api.NewJobsHandler(jobStore)
+		httpAPI.RegisterHandler(jobsHandler.Path, jobsHandler.Handle)
+
+		jobUpdateHandler := api.NewUpdateHandler(jobStore, runner)
+		httpAPI.RegisterHandler(jobUpdateHandler.Path, jobUpdateHandler.Handle)
+
+		scanHandler := api.NewScanHandler(jobStore, runner)
+		httpAPI.RegisterHandler(scanHandler.Path, scanHandler.Handle)
+
+		containersHandler := api.NewContainersHandler(buildContainersLister(filter))
+		httpAPI.RegisterHandler(containersHandler.Path, containersHandler.Handle)
+
 		// If polling isn't enabled the scheduler is never started, and
 		// we need to trigger the startup messages manually.
 		if !unblockHTTPAPI {
@@ -199,6 +326,23 @@ func Run(c *cobra.Command, names []string) {
 	if enableMetricsAPI {
 		metricsHandler := apiMetrics.New()
 		httpAPI.RegisterHandler(metricsHandler.Path, metricsHandler.Handle)
+
+		// cluster.RegisterMetrics/throttle.RegisterMetrics register
+		// Prometheus gauges on the default registry metricsHandler serves,
+		// so leadership and registry-throttle state show up alongside the
+		// update/scan metrics instead of only on their own JSON endpoints
+		// below (kept for operators already polling those directly).
+		if coordinator != nil {
+			cluster.RegisterMetrics(coordinator, clusterID)
+
+			statusHandler := cluster.NewStatusHandler(coordinator, clusterID)
+			httpAPI.RegisterHandler(statusHandler.Path, statusHandler.Handle)
+		}
+
+		throttle.RegisterMetrics(registryGate)
+
+		throttleHandler := throttle.NewStatsHandler(registryGate)
+		httpAPI.RegisterHandler(throttleHandler.Path, throttleHandler.Handle)
 	}
 
 	if err := httpAPI.Start(enableUpdateAPI && !unblockHTTPAPI); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -297,8 +441,7 @@ func writeStartupMessage(c *cobra.Command, sched time.Time, filtering string) {
 	}
 
 	if enableUpdateAPI {
-		// TODO: make listen port configurable
-		startupLog.Info("The HTTP API is enabled at :8080.")
+		startupLog.Info("The HTTP API is enabled at " + httpAPIListen + ".")
 	}
 
 	if !noStartupMessage {
@@ -311,6 +454,25 @@ func writeStartupMessage(c *cobra.Command, sched time.Time, filtering string) {
 	}
 }
 
+// isClusterLeader reports whether this instance should proceed with the
+// current scheduler tick. Instances not participating in a cluster (no
+// --cluster-id set) are always their own leader. A Campaign error fails
+// closed: skipping a tick is cheap, but two instances both believing they're
+// leader while the coordination backend is unreachable is exactly the
+// split-brain this package exists to prevent.
+func isClusterLeader() bool {
+	if coordinator == nil {
+		return true
+	}
+
+	won, err := coordinator.Campaign(context.Background())
+	if err != nil {
+		log.Error("cluster leader election failed, skipping this tick: ", err)
+		return false
+	}
+	return won
+}
+
 func runUpgradesOnSchedule(c *cobra.Command, filter t.Filter, filtering string, lock chan bool) error {
 	if lock == nil {
 		lock = make(chan bool, 1)
@@ -321,6 +483,12 @@ func runUpgradesOnSchedule(c *cobra.Command, filter t.Filter, filtering string,
 	err := scheduler.AddFunc(
 		scheduleSpec,
 		func() {
+			if !isClusterLeader() {
+				metrics.RegisterScan(nil)
+				log.Debug("Skipping this tick: another instance in the cluster is leader.")
+				return
+			}
+
 			select {
 			case v := <-lock:
 				defer func() { lock <- v }()
@@ -370,8 +538,12 @@ func runUpdatesWithNotifications(filter t.Filter) *metrics.Metric {
 		RollingRestart:  rollingRestart,
 		LabelPrecedence: labelPrecedence,
 		NoPull:          noPull,
+		// Strategy governs how actions.Update batches and rolls out the
+		// containers it finds stale; it defaults to the historical
+		// all-at-once behaviour when --update-strategy isn't set.
+		Strategy: rolloutStrategy,
 	}
-	result, err := actions.Update(client, updateParams)
+	result, err := actions.Update(client, updateParams, registryGate)
 	if err != nil {
 		log.Error(err)
 	}
@@ -384,3 +556,122 @@ func runUpdatesWithNotifications(filter t.Filter) *metrics.Metric {
 	}).Info("Session done")
 	return metricResults
 }
+
+// buildRunner adapts actions.Update into the api.Runner shape consumed by
+// the /v1/update and /v1/scan handlers, so both report the same
+// per-container outcome as a scheduled run. opts.DryRun forces monitor-only
+// behaviour for that single call, regardless of --monitor-only; opts.Strategy,
+// when set, overrides --update-strategy for that single call instead of
+// reusing the server's configured rolloutStrategy. lock is the same
+// updateLock the scheduler uses, so a job triggered over the API can never
+// run concurrently with a scheduled update.
+func buildRunner(filter t.Filter, lock chan bool) api.Runner {
+	return func(opts api.RunOptions) ([]api.ContainerOutcome, error) {
+		v := <-lock
+		defer func() { lock <- v }()
+
+		rollout := rolloutStrategy
+		if opts.Strategy != "" {
+			s, err := strategy.New(opts.Strategy, strategy.Options{
+				CanaryPercentage: canaryPercentage,
+				StageLabel:       stageLabel,
+				StageWait:        stageWait,
+			})
+			if err != nil {
+				return nil, err
+			}
+			rollout = s
+		}
+
+		updateParams := t.UpdateParams{
+			Filter:          filter,
+			Cleanup:         cleanup,
+			NoRestart:       noRestart,
+			Timeout:         timeout,
+			MonitorOnly:     monitorOnly || opts.DryRun,
+			LifecycleHooks:  lifecycleHooks,
+			RollingRestart:  rollingRestart,
+			LabelPrecedence: labelPrecedence,
+			NoPull:          noPull,
+			Strategy:        rollout,
+		}
+
+		result, err := actions.Update(client, updateParams, registryGate)
+		if err != nil {
+			return nil, err
+		}
+
+		updated := make(map[string]bool)
+		failed := make(map[string]string)
+		for _, c := range result.Updated() {
+			updated[c.Name()] = true
+		}
+		for _, c := range result.Failed() {
+			failed[c.Name()] = c.Error().Error()
+		}
+
+		outcomes := make([]api.ContainerOutcome, 0, len(result.Scanned()))
+		for _, c := range result.Scanned() {
+			outcomes = append(outcomes, api.ContainerOutcome{
+				Name:    c.Name(),
+				Image:   c.ImageName(),
+				Updated: updated[c.Name()],
+				Error:   failed[c.Name()],
+			})
+		}
+		return outcomes, nil
+	}
+}
+
+// buildContainersLister adapts the Docker client into the api.ContainersLister
+// shape consumed by the /v1/containers handler. Like actions.Update, it
+// consults registryGate before each staleness check so polling
+// /v1/containers can't bypass --registry-rate/--head-cache-ttl.
+func buildContainersLister(filter t.Filter) api.ContainersLister {
+	return func() ([]api.ContainerInfo, error) {
+		containers, err := client.ListContainers(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		infos := make([]api.ContainerInfo, 0, len(containers))
+		for _, c := range containers {
+			registry, repo, tag := throttle.ParseRef(c.ImageName())
+
+			digest, useCache, err := registryGate.Check(context.Background(), registry, repo, tag)
+			if err != nil {
+				return nil, err
+			}
+
+			var stale bool
+			var latestDigest string
+			if useCache {
+				// A fresh cached digest, or an active 429 backoff (which
+				// reports the last-seen digest, or none if there is none),
+				// short-circuits the registry round-trip IsContainerStale
+				// would otherwise make.
+				latestDigest = digest
+				stale = digest != "" && digest != string(c.ImageID())
+			} else {
+				var latestImage t.ImageID
+				stale, latestImage, err = client.IsContainerStale(c, t.UpdateParams{NoPull: noPull})
+				if err == nil {
+					registryGate.Observe(registry, repo, tag, string(latestImage), 0)
+					latestDigest = string(latestImage)
+				}
+			}
+
+			info := api.ContainerInfo{
+				Name:          c.Name(),
+				CurrentImage:  c.ImageName(),
+				CurrentDigest: string(c.ImageID()),
+				Stale:         stale,
+			}
+			if stale {
+				info.LatestDigest = latestDigest
+			}
+			infos = append(infos, info)
+		}
+		return infos, nil
+	}
+}
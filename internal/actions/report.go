@@ -0,0 +1,48 @@
+package actions
+
+import "github.com/beatkind/watchtower/pkg/container"
+
+// ContainerReport describes what happened to a single container during an
+// Update.
+type ContainerReport struct {
+	container container.Container
+	err       error
+}
+
+// Name returns the container's name.
+func (r ContainerReport) Name() string { return r.container.Name() }
+
+// ImageName returns the container's image reference.
+func (r ContainerReport) ImageName() string { return r.container.ImageName() }
+
+// Error returns the error that caused this container to fail, or nil for a
+// scanned or updated container.
+func (r ContainerReport) Error() error { return r.err }
+
+// Report is the result of a single Update call.
+type Report struct {
+	scanned []ContainerReport
+	updated []ContainerReport
+	failed  []ContainerReport
+}
+
+// Scanned returns every container Update looked at.
+func (r *Report) Scanned() []ContainerReport { return r.scanned }
+
+// Updated returns the containers Update rolled out successfully.
+func (r *Report) Updated() []ContainerReport { return r.updated }
+
+// Failed returns the containers Update failed to scan or roll out.
+func (r *Report) Failed() []ContainerReport { return r.failed }
+
+func (r *Report) addScanned(c container.Container) {
+	r.scanned = append(r.scanned, ContainerReport{container: c})
+}
+
+func (r *Report) addUpdated(c container.Container) {
+	r.updated = append(r.updated, ContainerReport{container: c})
+}
+
+func (r *Report) addFailed(c container.Container, err error) {
+	r.failed = append(r.failed, ContainerReport{container: c, err: err})
+}
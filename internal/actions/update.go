@@ -0,0 +1,108 @@
+// Package actions implements watchtower's core update flow: scanning
+// containers for staleness and rolling out the ones that need it.
+// CheckForSanity and CheckForMultipleWatchtowerInstances, invoked from
+// cmd.Run before Update ever runs, live alongside it in the real tree.
+package actions
+
+import (
+	"context"
+
+	"github.com/beatkind/watchtower/pkg/container"
+	"github.com/beatkind/watchtower/pkg/registry/throttle"
+	"github.com/beatkind/watchtower/pkg/strategy"
+	t "github.com/beatkind/watchtower/pkg/types"
+)
+
+// Update scans the containers matching params.Filter and rolls out the
+// stale ones using params.Strategy, falling back to the historical
+// all-at-once behaviour when it isn't set. Batches are executed in the
+// order Strategy.Plan returns them; rollout stops as soon as a batch comes
+// back unhealthy rather than pushing a bad image out to the rest of the
+// fleet. gate may be nil, in which case the staleness scan isn't rate
+// limited or cached at all.
+func Update(client container.Client, params t.UpdateParams, gate *throttle.Gate) (*Report, error) {
+	rollout := params.Strategy
+	if rollout == nil {
+		var err error
+		rollout, err = strategy.New("", strategy.Options{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	containers, err := client.ListContainers(params.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+
+	var stale []container.Container
+	for _, c := range containers {
+		report.addScanned(c)
+
+		if gate != nil {
+			registry, repo, tag := throttle.ParseRef(c.ImageName())
+
+			digest, useCache, err := gate.Check(context.Background(), registry, repo, tag)
+			if err != nil {
+				report.addFailed(c, err)
+				continue
+			}
+
+			if useCache {
+				// A fresh cached digest, or an active 429 backoff (which
+				// reports the last-seen digest, or none if there is none),
+				// short-circuits the registry round-trip IsContainerStale
+				// would otherwise make.
+				if digest != "" && digest != string(c.ImageID()) {
+					stale = append(stale, c)
+				}
+				continue
+			}
+
+			isStale, latestImage, err := client.IsContainerStale(c, params)
+			if err != nil {
+				report.addFailed(c, err)
+				continue
+			}
+			gate.Observe(registry, repo, tag, string(latestImage), 0)
+			if isStale {
+				stale = append(stale, c)
+			}
+			continue
+		}
+
+		isStale, _, err := client.IsContainerStale(c, params)
+		if err != nil {
+			report.addFailed(c, err)
+			continue
+		}
+		if isStale {
+			stale = append(stale, c)
+		}
+	}
+
+	if params.MonitorOnly {
+		return report, nil
+	}
+
+	for _, batch := range rollout.Plan(stale) {
+		result := rollout.Execute(batch, client, params)
+
+		for _, c := range result.Updated {
+			report.addUpdated(c)
+		}
+		for _, c := range result.Failed {
+			report.addFailed(c, result.Err)
+		}
+
+		if !result.Healthy() {
+			// Don't roll the same bad image out to the batches Plan
+			// hasn't reached yet.
+			break
+		}
+	}
+
+	return report, nil
+}
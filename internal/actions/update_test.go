@@ -0,0 +1,127 @@
+package actions
+
+import (
+	"time"
+
+	"testing"
+
+	"github.com/beatkind/watchtower/pkg/container"
+	"github.com/beatkind/watchtower/pkg/registry/throttle"
+	wt "github.com/beatkind/watchtower/pkg/types"
+)
+
+type fakeContainer struct {
+	name    string
+	imageID wt.ImageID
+}
+
+func (f fakeContainer) Name() string        { return f.name }
+func (f fakeContainer) ImageName() string   { return "library/" + f.name }
+func (f fakeContainer) ImageID() wt.ImageID { return f.imageID }
+
+// fakeClient is a minimal container.Client double recording which
+// containers were scanned/stopped/started so Update's gate wiring and
+// rollout calls can be asserted on directly.
+type fakeClient struct {
+	containers []container.Container
+	stale      map[string]wt.ImageID // container name -> latest image, if stale
+
+	scanned []string
+	started []string
+}
+
+func (f *fakeClient) ListContainers(wt.Filter) ([]container.Container, error) {
+	return f.containers, nil
+}
+
+func (f *fakeClient) IsContainerStale(c container.Container, _ wt.UpdateParams) (bool, wt.ImageID, error) {
+	f.scanned = append(f.scanned, c.Name())
+	if latest, ok := f.stale[c.Name()]; ok {
+		return true, latest, nil
+	}
+	return false, "", nil
+}
+
+func (f *fakeClient) StopContainer(container.Container, time.Duration) error { return nil }
+
+func (f *fakeClient) StartContainer(c container.Container) (wt.ContainerID, error) {
+	f.started = append(f.started, c.Name())
+	return wt.ContainerID(c.Name() + "-new"), nil
+}
+
+func (f *fakeClient) RenameContainer(container.Container, string) error { return nil }
+
+func (f *fakeClient) GetContainer(id wt.ContainerID) (container.Container, error) {
+	return fakeContainer{name: string(id)}, nil
+}
+
+func (f *fakeClient) ExecuteCommand(wt.ContainerID, string, int) (bool, error) { return false, nil }
+
+func TestUpdateRollsOutStaleContainersAllAtOnce(t *testing.T) {
+	client := &fakeClient{
+		containers: []container.Container{
+			fakeContainer{name: "web", imageID: "sha256:old"},
+			fakeContainer{name: "db", imageID: "sha256:old"},
+		},
+		stale: map[string]wt.ImageID{"web": "sha256:new"},
+	}
+
+	report, err := Update(client, wt.UpdateParams{}, nil)
+	if err != nil {
+		t.Fatalf("Update returned an error: %v", err)
+	}
+
+	if len(report.Scanned()) != 2 {
+		t.Errorf("expected both containers scanned, got %+v", report.Scanned())
+	}
+	if len(report.Updated()) != 1 || report.Updated()[0].Name() != "web" {
+		t.Errorf("expected only web to be updated, got %+v", report.Updated())
+	}
+	if len(client.started) != 1 || client.started[0] != "web" {
+		t.Errorf("expected only web to be started, started=%v", client.started)
+	}
+}
+
+func TestUpdateSkipsIsContainerStaleOnFreshCache(t *testing.T) {
+	client := &fakeClient{
+		containers: []container.Container{
+			fakeContainer{name: "web", imageID: "sha256:old"},
+		},
+		stale: map[string]wt.ImageID{"web": "sha256:new"},
+	}
+	gate := throttle.NewGate(throttle.Config{Default: throttle.Limits{CacheTTL: time.Minute}})
+	gate.Observe("docker.io", "library/web", "latest", "sha256:new", 0)
+
+	report, err := Update(client, wt.UpdateParams{}, gate)
+	if err != nil {
+		t.Fatalf("Update returned an error: %v", err)
+	}
+
+	if len(client.scanned) != 0 {
+		t.Errorf("expected the cached digest to short-circuit IsContainerStale, scanned=%v", client.scanned)
+	}
+	if len(report.Updated()) != 1 || report.Updated()[0].Name() != "web" {
+		t.Errorf("expected web to still be rolled out from the cached digest, got %+v", report.Updated())
+	}
+}
+
+func TestUpdateMonitorOnlySkipsRollout(t *testing.T) {
+	client := &fakeClient{
+		containers: []container.Container{
+			fakeContainer{name: "web", imageID: "sha256:old"},
+		},
+		stale: map[string]wt.ImageID{"web": "sha256:new"},
+	}
+
+	report, err := Update(client, wt.UpdateParams{MonitorOnly: true}, nil)
+	if err != nil {
+		t.Fatalf("Update returned an error: %v", err)
+	}
+
+	if len(report.Updated()) != 0 {
+		t.Errorf("expected MonitorOnly to skip rollout, updated=%+v", report.Updated())
+	}
+	if len(client.started) != 0 {
+		t.Errorf("expected no container to be started under MonitorOnly, started=%v", client.started)
+	}
+}
@@ -0,0 +1,170 @@
+// Package api implements watchtower's HTTP API, used to trigger updates on
+// demand and to expose metrics, job status and container state to external
+// tooling.
+package api
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultAddr is used when Options.Addr isn't set.
+const DefaultAddr = ":8080"
+
+// Options configures how the API server listens.
+type Options struct {
+	// Addr is the listen address, e.g. ":8080" or "127.0.0.1:8081".
+	Addr string
+	// TLSCertFile and TLSKeyFile enable TLS when both are set.
+	TLSCertFile string
+	TLSKeyFile  string
+	// ClientCAFile, when set alongside TLS, requires and verifies client
+	// certificates signed by this CA, enabling mutual TLS.
+	ClientCAFile string
+}
+
+// API is watchtower's HTTP server. Handlers are registered before Start is
+// called; Start blocks, or not, depending on whether periodic polling is
+// also driving updates.
+type API struct {
+	Token string
+
+	mux         *http.ServeMux
+	server      *http.Server
+	hasHandlers bool
+}
+
+// New creates an API server listening on DefaultAddr with no TLS. Use
+// NewWithOptions to configure the listen address or TLS.
+func New(token string) *API {
+	api, err := NewWithOptions(token, Options{})
+	if err != nil {
+		// Options{} never enables TLS, so this can't actually fail.
+		panic(err)
+	}
+	return api
+}
+
+// NewWithOptions creates an API server using the given listen/TLS options.
+func NewWithOptions(token string, opts Options) (*API, error) {
+	tlsConfig, err := buildTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	api := &API{
+		Token: token,
+		mux:   mux,
+		server: &http.Server{
+			Addr:      addrOrDefault(opts.Addr),
+			Handler:   withAuth(token, mux),
+			TLSConfig: tlsConfig,
+		},
+	}
+	return api, nil
+}
+
+func addrOrDefault(addr string) string {
+	if addr == "" {
+		return DefaultAddr
+	}
+	return addr
+}
+
+func buildTLSConfig(opts Options) (*tls.Config, error) {
+	if opts.TLSCertFile == "" && opts.TLSKeyFile == "" {
+		return nil, nil
+	}
+	if opts.TLSCertFile == "" || opts.TLSKeyFile == "" {
+		return nil, errors.New("both --http-api-tls-cert and --http-api-tls-key must be set to enable TLS")
+	}
+
+	cert, err := tls.LoadX509KeyPair(opts.TLSCertFile, opts.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading API TLS certificate: %w", err)
+	}
+
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+
+	if opts.ClientCAFile != "" {
+		caCert, err := os.ReadFile(opts.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading client CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("no certificates found in client CA file")
+		}
+
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return cfg, nil
+}
+
+// withAuth rejects requests that don't carry the configured bearer token.
+// An empty token disables authentication entirely.
+func withAuth(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" {
+			got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RegisterFunc registers fn to handle requests under path.
+func (api *API) RegisterFunc(path string, fn http.HandlerFunc) {
+	api.mux.HandleFunc(path, fn)
+	api.hasHandlers = true
+}
+
+// RegisterHandler registers handler to handle requests under path.
+func (api *API) RegisterHandler(path string, handler http.Handler) {
+	api.mux.Handle(path, handler)
+	api.hasHandlers = true
+}
+
+// Start begins serving the registered handlers. If block is true, Start
+// does not return until the server shuts down; otherwise it starts the
+// server in the background and returns immediately.
+func (api *API) Start(block bool) error {
+	if !api.hasHandlers {
+		log.Debug("No handlers registered; the HTTP API will not start.")
+		return nil
+	}
+
+	if block {
+		return api.serve()
+	}
+
+	go func() {
+		if err := api.serve(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("failed to start API", err)
+		}
+	}()
+	return nil
+}
+
+func (api *API) serve() error {
+	log.Info("Starting the Watchtower API...")
+	if api.server.TLSConfig != nil {
+		return api.server.ListenAndServeTLS("", "")
+	}
+	return api.server.ListenAndServe()
+}
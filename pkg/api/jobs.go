@@ -0,0 +1,272 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JobStatus is the lifecycle state of a Job.
+type JobStatus string
+
+// Job lifecycle states.
+const (
+	JobRunning JobStatus = "running"
+	JobDone    JobStatus = "done"
+	JobFailed  JobStatus = "failed"
+)
+
+// ContainerOutcome reports what happened to a single container during a Job.
+type ContainerOutcome struct {
+	Name    string `json:"name"`
+	Image   string `json:"image"`
+	Updated bool   `json:"updated"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Job tracks one update or scan run started through a JobStore, queryable
+// through the /v1/jobs endpoint. Only the HTTP API's /v1/update and /v1/scan
+// handlers start Jobs; scheduled runs are not recorded as Jobs.
+type Job struct {
+	ID         string             `json:"id"`
+	Status     JobStatus          `json:"status"`
+	DryRun     bool               `json:"dryRun"`
+	Strategy   string             `json:"strategy,omitempty"`
+	StartedAt  time.Time          `json:"startedAt"`
+	FinishedAt time.Time          `json:"finishedAt,omitempty"`
+	Scanned    int                `json:"scanned"`
+	Updated    int                `json:"updated"`
+	Failed     int                `json:"failed"`
+	Containers []ContainerOutcome `json:"containers,omitempty"`
+	Error      string             `json:"error,omitempty"`
+}
+
+// RunOptions configures a single Runner invocation.
+type RunOptions struct {
+	// DryRun requests a scan-only pass: containers are inspected but never
+	// pulled or restarted.
+	DryRun bool
+	// Strategy overrides the server's configured --update-strategy for this
+	// run only, using the same names that flag accepts (e.g. "canary",
+	// "blue-green", "staged"). Empty keeps the configured default.
+	Strategy string
+}
+
+// Runner performs a scan/update pass and returns the per-container outcome.
+type Runner func(opts RunOptions) ([]ContainerOutcome, error)
+
+// JobStore tracks in-flight and completed Jobs so API-triggered update and
+// scan runs are queryable through the same interface.
+type JobStore struct {
+	mu     sync.Mutex
+	jobs   map[string]*Job
+	nextID uint64
+}
+
+// NewJobStore creates an empty JobStore.
+func NewJobStore() *JobStore {
+	return &JobStore{jobs: make(map[string]*Job)}
+}
+
+// Start creates a new Job, runs it via run in the background, and returns
+// the Job immediately in its running state.
+func (s *JobStore) Start(opts RunOptions, run Runner) *Job {
+	s.mu.Lock()
+	s.nextID++
+	job := &Job{
+		ID:        "job-" + strconv.FormatUint(s.nextID, 10),
+		Status:    JobRunning,
+		DryRun:    opts.DryRun,
+		Strategy:  opts.Strategy,
+		StartedAt: time.Now(),
+	}
+	s.jobs[job.ID] = job
+	s.mu.Unlock()
+
+	go func() {
+		outcomes, err := run(opts)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		job.FinishedAt = time.Now()
+		job.Containers = outcomes
+		job.Scanned = len(outcomes)
+		for _, o := range outcomes {
+			switch {
+			case o.Updated:
+				job.Updated++
+			case o.Error != "":
+				job.Failed++
+			}
+		}
+
+		if err != nil {
+			job.Status = JobFailed
+			job.Error = err.Error()
+			return
+		}
+		job.Status = JobDone
+	}()
+
+	return job
+}
+
+// Get returns a snapshot of the Job with the given ID, or false if it isn't
+// known.
+func (s *JobStore) Get(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// UpdateHandler triggers an update job via POST /v1/update and returns its
+// job ID for polling via JobsHandler.
+type UpdateHandler struct {
+	Path string
+
+	store *JobStore
+	run   Runner
+}
+
+// NewUpdateHandler returns a handler mounted at /v1/update.
+func NewUpdateHandler(store *JobStore, run Runner) *UpdateHandler {
+	return &UpdateHandler{Path: "/v1/update", store: store, run: run}
+}
+
+// Handle starts a new update job and responds with its ID. An optional JSON
+// body of the form {"strategy": "canary"} overrides the server's configured
+// --update-strategy for this run only; a missing or empty body keeps it.
+func (h *UpdateHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		Strategy string `json:"strategy"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+		http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	job := h.store.Start(RunOptions{Strategy: body.Strategy}, h.run)
+	writeJSON(w, http.StatusAccepted, struct {
+		ID string `json:"id"`
+	}{ID: job.ID})
+}
+
+// ScanHandler performs a dry run via POST /v1/scan, reporting what would be
+// updated without pulling or restarting anything.
+type ScanHandler struct {
+	Path string
+
+	store *JobStore
+	run   Runner
+}
+
+// NewScanHandler returns a handler mounted at /v1/scan.
+func NewScanHandler(store *JobStore, run Runner) *ScanHandler {
+	return &ScanHandler{Path: "/v1/scan", store: store, run: run}
+}
+
+// Handle starts a new dry-run scan job and responds with its ID.
+func (h *ScanHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	job := h.store.Start(RunOptions{DryRun: true}, h.run)
+	writeJSON(w, http.StatusAccepted, struct {
+		ID string `json:"id"`
+	}{ID: job.ID})
+}
+
+// JobsHandler serves GET /v1/jobs/{id} with a Job's current status and
+// result.
+type JobsHandler struct {
+	Path string
+
+	store *JobStore
+}
+
+// NewJobsHandler returns a handler mounted at /v1/jobs/.
+func NewJobsHandler(store *JobStore) *JobsHandler {
+	return &JobsHandler{Path: "/v1/jobs/", store: store}
+}
+
+// Handle writes the Job identified by the path's trailing segment as JSON.
+func (h *JobsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, h.Path)
+	if id == "" {
+		http.Error(w, "missing job id", http.StatusBadRequest)
+		return
+	}
+
+	job, ok := h.store.Get(id)
+	if !ok {
+		http.Error(w, "unknown job id", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
+
+// ContainerInfo summarises one filter-matched container for GET
+// /v1/containers.
+type ContainerInfo struct {
+	Name          string `json:"name"`
+	CurrentImage  string `json:"currentImage"`
+	CurrentDigest string `json:"currentDigest"`
+	LatestDigest  string `json:"latestDigest,omitempty"`
+	Stale         bool   `json:"stale"`
+}
+
+// ContainersLister returns the current state of every filter-matched
+// container without performing any update.
+type ContainersLister func() ([]ContainerInfo, error)
+
+// ContainersHandler serves GET /v1/containers.
+type ContainersHandler struct {
+	Path string
+
+	list ContainersLister
+}
+
+// NewContainersHandler returns a handler mounted at /v1/containers.
+func NewContainersHandler(list ContainersLister) *ContainersHandler {
+	return &ContainersHandler{Path: "/v1/containers", list: list}
+}
+
+// Handle writes the current container list as JSON.
+func (h *ContainersHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	containers, err := h.list()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, containers)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestJobStoreTracksCompletion(t *testing.T) {
+	store := NewJobStore()
+
+	job := store.Start(RunOptions{}, func(RunOptions) ([]ContainerOutcome, error) {
+		return []ContainerOutcome{{Name: "web", Updated: true}}, nil
+	})
+
+	got := waitForStatus(t, store, job.ID, JobDone)
+	if got.Updated != 1 {
+		t.Errorf("expected 1 updated container, got %d", got.Updated)
+	}
+	if got.Scanned != 1 {
+		t.Errorf("expected 1 scanned container, got %d", got.Scanned)
+	}
+}
+
+func TestJobStoreRecordsFailure(t *testing.T) {
+	store := NewJobStore()
+
+	job := store.Start(RunOptions{}, func(RunOptions) ([]ContainerOutcome, error) {
+		return nil, errors.New("boom")
+	})
+
+	got := waitForStatus(t, store, job.ID, JobFailed)
+	if got.Error != "boom" {
+		t.Errorf("expected error %q, got %q", "boom", got.Error)
+	}
+}
+
+func TestJobStoreUnknownID(t *testing.T) {
+	store := NewJobStore()
+	if _, ok := store.Get("job-does-not-exist"); ok {
+		t.Error("expected an unknown job ID to not be found")
+	}
+}
+
+func TestUpdateHandlerPassesStrategyFromRequestBody(t *testing.T) {
+	store := NewJobStore()
+	var gotStrategy string
+	handler := NewUpdateHandler(store, func(opts RunOptions) ([]ContainerOutcome, error) {
+		gotStrategy = opts.Strategy
+		return nil, nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/update", bytes.NewBufferString(`{"strategy":"canary"}`))
+	rec := httptest.NewRecorder()
+	handler.Handle(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", rec.Code)
+	}
+
+	job, ok := store.Get(jsonID(t, rec))
+	if !ok {
+		t.Fatalf("expected the job to be queryable")
+	}
+	waitForStatus(t, store, job.ID, JobDone)
+
+	if gotStrategy != "canary" {
+		t.Errorf("expected the runner to see strategy %q, got %q", "canary", gotStrategy)
+	}
+}
+
+func TestUpdateHandlerDefaultsStrategyWithNoBody(t *testing.T) {
+	store := NewJobStore()
+	var gotStrategy string
+	handler := NewUpdateHandler(store, func(opts RunOptions) ([]ContainerOutcome, error) {
+		gotStrategy = opts.Strategy
+		return nil, nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/update", nil)
+	rec := httptest.NewRecorder()
+	handler.Handle(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", rec.Code)
+	}
+	if job, ok := store.Get(jsonID(t, rec)); ok {
+		waitForStatus(t, store, job.ID, JobDone)
+	}
+
+	if gotStrategy != "" {
+		t.Errorf("expected no strategy override with no body, got %q", gotStrategy)
+	}
+}
+
+func jsonID(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+
+	var body struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding response body: %v", err)
+	}
+	return body.ID
+}
+
+func waitForStatus(t *testing.T, store *JobStore, id string, want JobStatus) Job {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if got, ok := store.Get(id); ok && got.Status == want {
+			return got
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	t.Fatalf("job %s did not reach status %q in time", id, want)
+	return Job{}
+}
@@ -0,0 +1,145 @@
+// Package cluster coordinates update decisions across a fleet of watchtower
+// instances so only one host (or one host per cluster ID) pulls images and
+// performs updates at a time, while the rest skip their scheduled runs.
+// Followers receive no signal from the leader; one of them takes over only
+// once the leader's lease expires or the leader resigns.
+//
+// CheckForMultipleWatchtowerInstances already guards against duplicate
+// instances on a single Docker host; Coordinator extends the same idea
+// across hosts via a pluggable Store.
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// DefaultLeaseTTL is how long a leadership claim is valid before it must be
+// renewed. A host that stops renewing (crashes, loses network) cedes
+// leadership to a follower within this window.
+const DefaultLeaseTTL = 30 * time.Second
+
+// Config selects and configures a coordination backend.
+type Config struct {
+	// Backend is one of "registry" (default), "redis", "consul" or "etcd".
+	Backend string
+	// ID identifies this cluster of watchtower instances; instances using
+	// different IDs never contend for the same lease. Typically one ID per
+	// fleet, or one per image when pulls should be coordinated per-image.
+	ID string
+	// Endpoint is the backend-specific address, e.g. a Redis or Consul URL.
+	Endpoint string
+	// LeaseTTL overrides DefaultLeaseTTL.
+	LeaseTTL time.Duration
+}
+
+// Store is the minimal compare-and-swap primitive a coordination backend
+// must provide. Leadership is modelled as a single key holding the current
+// leader's holder ID; a host becomes leader by winning a CAS on that key.
+type Store interface {
+	// Get returns the current value of key, and false if it does not exist
+	// or has expired.
+	Get(ctx context.Context, key string) (value string, found bool, err error)
+	// CompareAndSwap sets key to newValue if and only if its current value
+	// equals oldValue (or it doesn't exist, when oldValue is ""), with the
+	// write expiring after ttl. It reports whether the swap succeeded.
+	CompareAndSwap(ctx context.Context, key, oldValue, newValue string, ttl time.Duration) (bool, error)
+}
+
+// Coordinator tracks whether this instance currently holds leadership for a
+// Config.ID.
+type Coordinator struct {
+	store    Store
+	key      string
+	holderID string
+	ttl      time.Duration
+
+	leader bool
+}
+
+// New constructs a Coordinator for the given backend. holderID should
+// uniquely identify this watchtower instance, e.g. hostname+pid.
+func New(cfg Config, holderID string) (*Coordinator, error) {
+	ttl := cfg.LeaseTTL
+	if ttl <= 0 {
+		ttl = DefaultLeaseTTL
+	}
+
+	store, err := newStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Coordinator{
+		store:    store,
+		key:      "watchtower/cluster/" + cfg.ID + "/leader",
+		holderID: holderID,
+		ttl:      ttl,
+	}, nil
+}
+
+func newStore(cfg Config) (Store, error) {
+	switch cfg.Backend {
+	case "", "registry":
+		// Keep one lease file per cluster ID, so clusters with different
+		// IDs sharing a lease directory don't contend for the same lease.
+		store := newRegistryStore()
+		store.path = filepath.Join(filepath.Dir(store.path), cfg.ID, filepath.Base(store.path))
+		return store, nil
+	case "redis":
+		return newRedisStore(cfg.Endpoint)
+	case "consul":
+		return newConsulStore(cfg.Endpoint)
+	case "etcd":
+		return newEtcdStore(cfg.Endpoint)
+	default:
+		return nil, fmt.Errorf("unknown cluster backend %q", cfg.Backend)
+	}
+}
+
+// Campaign attempts to (re-)claim leadership. It is safe, and expected, to
+// call this on every scheduler tick: a current leader renews its lease by
+// winning the CAS against its own holder ID, and a follower whose leader's
+// lease has expired can win it instead.
+func (c *Coordinator) Campaign(ctx context.Context) (bool, error) {
+	current, found, err := c.store.Get(ctx, c.key)
+	if err != nil {
+		return false, err
+	}
+
+	old := ""
+	if found {
+		if current != c.holderID {
+			c.leader = false
+			return false, nil
+		}
+		old = current
+	}
+
+	won, err := c.store.CompareAndSwap(ctx, c.key, old, c.holderID, c.ttl)
+	if err != nil {
+		return false, err
+	}
+
+	c.leader = won
+	return won, nil
+}
+
+// IsLeader reports the outcome of the most recent Campaign call without
+// contacting the backend.
+func (c *Coordinator) IsLeader() bool {
+	return c.leader
+}
+
+// Resign gives up leadership immediately rather than waiting for the lease
+// to expire, so a follower can take over without delay.
+func (c *Coordinator) Resign(ctx context.Context) error {
+	if !c.leader {
+		return nil
+	}
+	_, err := c.store.CompareAndSwap(ctx, c.key, c.holderID, "", 0)
+	c.leader = false
+	return err
+}
@@ -0,0 +1,66 @@
+package cluster
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestCoordinator(t *testing.T, holderID string) *Coordinator {
+	t.Helper()
+	store := &registryStore{path: filepath.Join(t.TempDir(), "leader.json")}
+	return &Coordinator{store: store, key: "test/leader", holderID: holderID, ttl: time.Minute}
+}
+
+func TestCampaignWinsWhenUnclaimed(t *testing.T) {
+	c := newTestCoordinator(t, "host-a")
+
+	won, err := c.Campaign(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !won || !c.IsLeader() {
+		t.Fatal("expected to win an unclaimed lease")
+	}
+}
+
+func TestCampaignLosesToAnExistingLeader(t *testing.T) {
+	store := &registryStore{path: filepath.Join(t.TempDir(), "leader.json")}
+	leader := &Coordinator{store: store, key: "test/leader", holderID: "host-a", ttl: time.Minute}
+	follower := &Coordinator{store: store, key: "test/leader", holderID: "host-b", ttl: time.Minute}
+
+	if _, err := leader.Campaign(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	won, err := follower.Campaign(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if won || follower.IsLeader() {
+		t.Fatal("follower should not win a lease already held by another instance")
+	}
+}
+
+func TestResignAllowsAFollowerToTakeOver(t *testing.T) {
+	store := &registryStore{path: filepath.Join(t.TempDir(), "leader.json")}
+	leader := &Coordinator{store: store, key: "test/leader", holderID: "host-a", ttl: time.Minute}
+	follower := &Coordinator{store: store, key: "test/leader", holderID: "host-b", ttl: time.Minute}
+
+	ctx := context.Background()
+	if _, err := leader.Campaign(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := leader.Resign(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	won, err := follower.Campaign(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !won {
+		t.Fatal("expected follower to win the lease after the leader resigned")
+	}
+}
@@ -0,0 +1,107 @@
+package cluster
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// consulStore coordinates leadership via Consul's KV store, using its
+// built-in check-and-set on the key's ModifyIndex together with a session so
+// the lease is released automatically if this process dies.
+type consulStore struct {
+	kv      *api.KV
+	session *api.Session
+
+	mu        sync.Mutex
+	sessionID string
+}
+
+func newConsulStore(endpoint string) (*consulStore, error) {
+	cfg := api.DefaultConfig()
+	if endpoint != "" {
+		cfg.Address = endpoint
+	}
+
+	client, err := api.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &consulStore{kv: client.KV(), session: client.Session()}, nil
+}
+
+// ensureSession returns this store's Consul session, creating one only the
+// first time it's needed or after the cached one has stopped renewing.
+// CompareAndSwap is called on every cron tick, and minting a fresh session
+// each time would leak one session per tick for as long as the process runs.
+func (s *consulStore) ensureSession(ttl time.Duration) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sessionID != "" {
+		if _, _, err := s.session.Renew(s.sessionID, nil); err == nil {
+			return s.sessionID, nil
+		}
+		// The session expired or was invalidated out from under us; fall
+		// through and create a replacement.
+		s.sessionID = ""
+	}
+
+	id, _, err := s.session.Create(&api.SessionEntry{
+		TTL:      ttl.String(),
+		Behavior: api.SessionBehaviorDelete,
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	s.sessionID = id
+	return id, nil
+}
+
+func (s *consulStore) Get(_ context.Context, key string) (string, bool, error) {
+	pair, _, err := s.kv.Get(key, nil)
+	if err != nil {
+		return "", false, err
+	}
+	if pair == nil {
+		return "", false, nil
+	}
+	return string(pair.Value), true, nil
+}
+
+func (s *consulStore) CompareAndSwap(_ context.Context, key, oldValue, newValue string, ttl time.Duration) (bool, error) {
+	sessionID, err := s.ensureSession(ttl)
+	if err != nil {
+		return false, err
+	}
+
+	current, meta, err := s.kv.Get(key, nil)
+	if err != nil {
+		return false, err
+	}
+	if current != nil && string(current.Value) != oldValue {
+		return false, nil
+	}
+
+	if newValue == "" {
+		// Releasing the lease: delete the key, so a follower's Get finds it
+		// unclaimed instead of held by an empty holder ID.
+		if current == nil {
+			return true, nil
+		}
+		ok, _, err := s.kv.DeleteCAS(&api.KVPair{Key: key, ModifyIndex: meta.LastIndex}, nil)
+		return ok, err
+	}
+
+	pair := &api.KVPair{Key: key, Value: []byte(newValue), Session: sessionID}
+	if current != nil {
+		pair.ModifyIndex = meta.LastIndex
+	}
+
+	ok, _, err := s.kv.CAS(pair, nil)
+	return ok, err
+}
@@ -0,0 +1,78 @@
+package cluster
+
+import (
+	"context"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// etcdStore coordinates leadership using etcd's transactional
+// compare-and-swap primitives together with a lease, so an unrenewed claim
+// expires automatically.
+type etcdStore struct {
+	client *clientv3.Client
+}
+
+func newEtcdStore(endpoint string) (*etcdStore, error) {
+	if endpoint == "" {
+		endpoint = "127.0.0.1:2379"
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{endpoint},
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &etcdStore{client: client}, nil
+}
+
+func (s *etcdStore) Get(ctx context.Context, key string) (string, bool, error) {
+	resp, err := s.client.Get(ctx, key)
+	if err != nil {
+		return "", false, err
+	}
+	if len(resp.Kvs) == 0 {
+		return "", false, nil
+	}
+	return string(resp.Kvs[0].Value), true, nil
+}
+
+func (s *etcdStore) CompareAndSwap(ctx context.Context, key, oldValue, newValue string, ttl time.Duration) (bool, error) {
+	if newValue == "" {
+		// Releasing the lease: delete the key, so a follower's Get finds it
+		// unclaimed instead of held by an empty holder ID.
+		resp, err := s.client.Txn(ctx).
+			If(clientv3.Compare(clientv3.Value(key), "=", oldValue)).
+			Then(clientv3.OpDelete(key)).
+			Commit()
+		if err != nil {
+			return false, err
+		}
+		return resp.Succeeded, nil
+	}
+
+	lease, err := s.client.Grant(ctx, int64(ttl.Seconds()))
+	if err != nil {
+		return false, err
+	}
+
+	var cmp clientv3.Cmp
+	if oldValue == "" {
+		cmp = clientv3.Compare(clientv3.CreateRevision(key), "=", 0)
+	} else {
+		cmp = clientv3.Compare(clientv3.Value(key), "=", oldValue)
+	}
+
+	resp, err := s.client.Txn(ctx).
+		If(cmp).
+		Then(clientv3.OpPut(key, newValue, clientv3.WithLease(lease.ID))).
+		Commit()
+	if err != nil {
+		return false, err
+	}
+	return resp.Succeeded, nil
+}
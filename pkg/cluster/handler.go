@@ -0,0 +1,29 @@
+package cluster
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// StatusHandler exposes this instance's leadership state over the metrics
+// API so operators can see which host in the fleet is currently active.
+type StatusHandler struct {
+	Path string
+
+	coordinator *Coordinator
+	clusterID   string
+}
+
+// NewStatusHandler returns a handler mounted at /v1/cluster/status.
+func NewStatusHandler(coordinator *Coordinator, clusterID string) *StatusHandler {
+	return &StatusHandler{Path: "/v1/cluster/status", coordinator: coordinator, clusterID: clusterID}
+}
+
+// Handle writes the current leadership state as JSON.
+func (h *StatusHandler) Handle(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		ClusterID string `json:"clusterId"`
+		Leader    bool   `json:"leader"`
+	}{ClusterID: h.clusterID, Leader: h.coordinator.IsLeader()})
+}
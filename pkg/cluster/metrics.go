@@ -0,0 +1,23 @@
+package cluster
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// RegisterMetrics registers a gauge reporting whether this instance
+// currently holds leadership for clusterID, so the existing Prometheus
+// metrics endpoint surfaces cluster state alongside the update/scan
+// metrics rather than only via StatusHandler's standalone JSON endpoint.
+func RegisterMetrics(coordinator *Coordinator, clusterID string) {
+	prometheus.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name:        "watchtower_cluster_leader",
+			Help:        "1 if this instance currently holds cluster leadership, 0 otherwise.",
+			ConstLabels: prometheus.Labels{"cluster_id": clusterID},
+		},
+		func() float64 {
+			if coordinator.IsLeader() {
+				return 1
+			}
+			return 0
+		},
+	))
+}
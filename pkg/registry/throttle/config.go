@@ -0,0 +1,36 @@
+package throttle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// LoadConfig reads per-registry rate/cache overrides from a JSON file at
+// path, in addition to the process-wide default limits. An empty path
+// returns def with no overrides.
+//
+// The file is a JSON object mapping registry host to its override, e.g.:
+//
+//	{
+//	  "docker.io": {"RateLimit": 2, "Burst": 4, "CacheTTL": "5m"}
+//	}
+func LoadConfig(path string, def Limits) (Config, error) {
+	cfg := Config{Default: def}
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("reading registry throttle config %s: %w", path, err)
+	}
+
+	var overrides map[string]Limits
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return cfg, fmt.Errorf("parsing registry throttle config %s: %w", path, err)
+	}
+
+	cfg.Overrides = overrides
+	return cfg, nil
+}
@@ -0,0 +1,26 @@
+package throttle
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// StatsHandler exposes the Gate's throttled/cached counts over the metrics
+// API so operators can see the effect of --registry-rate and
+// --head-cache-ttl.
+type StatsHandler struct {
+	Path string
+
+	gate *Gate
+}
+
+// NewStatsHandler returns a handler mounted at /v1/registry/throttle.
+func NewStatsHandler(gate *Gate) *StatsHandler {
+	return &StatsHandler{Path: "/v1/registry/throttle", gate: gate}
+}
+
+// Handle writes the current Stats as JSON.
+func (h *StatsHandler) Handle(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.gate.Stats())
+}
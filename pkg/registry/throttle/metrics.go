@@ -0,0 +1,26 @@
+package throttle
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// RegisterMetrics registers gauges reporting gate's throttled/cached
+// counters, so the existing Prometheus metrics endpoint surfaces the effect
+// of --registry-rate and --head-cache-ttl alongside the update/scan
+// metrics rather than only via StatsHandler's standalone JSON endpoint.
+func RegisterMetrics(gate *Gate) {
+	prometheus.MustRegister(
+		prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Name: "watchtower_registry_throttled_total",
+				Help: "Number of registry checks delayed by --registry-rate.",
+			},
+			func() float64 { return float64(gate.Stats().Throttled) },
+		),
+		prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Name: "watchtower_registry_cached_total",
+				Help: "Number of registry checks served from the digest cache or an active 429 backoff.",
+			},
+			func() float64 { return float64(gate.Stats().Cached) },
+		),
+	)
+}
@@ -0,0 +1,39 @@
+package throttle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestThrottleGaugeFuncsTrackStats(t *testing.T) {
+	gate := NewGate(Config{Default: Limits{CacheTTL: time.Minute}})
+
+	throttled := prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{Name: "watchtower_registry_throttled_total"},
+		func() float64 { return float64(gate.Stats().Throttled) },
+	)
+	cached := prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{Name: "watchtower_registry_cached_total"},
+		func() float64 { return float64(gate.Stats().Cached) },
+	)
+
+	if got := testutil.ToFloat64(cached); got != 0 {
+		t.Fatalf("expected 0 cached before any checks, got %v", got)
+	}
+
+	gate.Observe("docker.io", "library/nginx", "latest", "sha256:abc", 0)
+	if _, _, err := gate.Check(context.Background(), "docker.io", "library/nginx", "latest"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := testutil.ToFloat64(cached); got != 1 {
+		t.Errorf("expected 1 cached hit, got %v", got)
+	}
+	if got := testutil.ToFloat64(throttled); got != 0 {
+		t.Errorf("expected 0 throttled, got %v", got)
+	}
+}
@@ -0,0 +1,28 @@
+package throttle
+
+import "strings"
+
+// ParseRef splits a container image reference such as
+// "registry.example.com/library/nginx:latest" into the registry, repository
+// and tag Gate.Check/Observe key on, defaulting to Docker Hub and "latest"
+// when either is omitted from the reference.
+func ParseRef(image string) (registry, repo, tag string) {
+	registry = "docker.io"
+	repo = image
+	tag = "latest"
+
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		tag = repo[i+1:]
+		repo = repo[:i]
+	}
+
+	if i := strings.Index(repo, "/"); i > 0 {
+		host := repo[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			registry = host
+			repo = repo[i+1:]
+		}
+	}
+
+	return registry, repo, tag
+}
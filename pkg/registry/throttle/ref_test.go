@@ -0,0 +1,24 @@
+package throttle
+
+import "testing"
+
+func TestParseRefDefaultsRegistryAndTag(t *testing.T) {
+	registry, repo, tag := ParseRef("library/nginx")
+	if registry != "docker.io" || repo != "library/nginx" || tag != "latest" {
+		t.Errorf("got %q %q %q", registry, repo, tag)
+	}
+}
+
+func TestParseRefWithRegistryAndTag(t *testing.T) {
+	registry, repo, tag := ParseRef("registry.example.com/team/app:v1.2.3")
+	if registry != "registry.example.com" || repo != "team/app" || tag != "v1.2.3" {
+		t.Errorf("got %q %q %q", registry, repo, tag)
+	}
+}
+
+func TestParseRefWithoutRegistryHost(t *testing.T) {
+	registry, repo, tag := ParseRef("nginx:1.25")
+	if registry != "docker.io" || repo != "nginx" || tag != "1.25" {
+		t.Errorf("got %q %q %q", registry, repo, tag)
+	}
+}
@@ -0,0 +1,159 @@
+// Package throttle rate-limits and caches registry digest checks so a large
+// fleet of watchtower instances, or a long container list scanned on every
+// tick, doesn't hammer a registry with HEAD/GET requests.
+package throttle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Limits configures the throttle for one registry host.
+type Limits struct {
+	// RateLimit is the sustained number of requests per second allowed
+	// against this registry.
+	RateLimit float64
+	// Burst is the maximum number of requests that may be made back to
+	// back before RateLimit kicks in.
+	Burst int
+	// CacheTTL is how long a digest lookup is served from cache before the
+	// registry is consulted again.
+	CacheTTL time.Duration
+}
+
+// UnmarshalJSON lets a config file spell CacheTTL as a duration string such
+// as "5m" rather than a raw number of nanoseconds.
+func (l *Limits) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		RateLimit float64
+		Burst     int
+		CacheTTL  string
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	l.RateLimit = raw.RateLimit
+	l.Burst = raw.Burst
+	if raw.CacheTTL != "" {
+		ttl, err := time.ParseDuration(raw.CacheTTL)
+		if err != nil {
+			return fmt.Errorf("invalid CacheTTL %q: %w", raw.CacheTTL, err)
+		}
+		l.CacheTTL = ttl
+	}
+	return nil
+}
+
+// Config holds the default Limits plus any per-registry overrides, usually
+// loaded via LoadConfig.
+type Config struct {
+	Default   Limits
+	Overrides map[string]Limits
+}
+
+// For returns the Limits that apply to registry, falling back to the
+// configured default when there's no override.
+func (c Config) For(registry string) Limits {
+	if l, ok := c.Overrides[registry]; ok {
+		return l
+	}
+	return c.Default
+}
+
+// Stats holds the counters surfaced via the metrics API.
+type Stats struct {
+	Throttled uint64
+	Cached    uint64
+}
+
+// Gate combines per-registry rate limiting and digest caching behind a
+// single call used from the update path before a HEAD/GET request reaches
+// the registry.
+type Gate struct {
+	cfg      Config
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	cache    *Cache
+
+	throttled atomic.Uint64
+	cached    atomic.Uint64
+}
+
+// NewGate creates a Gate from cfg.
+func NewGate(cfg Config) *Gate {
+	return &Gate{cfg: cfg, limiters: make(map[string]*rate.Limiter), cache: NewCache()}
+}
+
+// Check returns a cached digest for registry/repo/tag when one is fresh, or
+// the registry is in a 429 backoff window. Otherwise it waits out that
+// registry's rate limit and reports that the caller should perform the
+// request itself and report the outcome via Observe. Stats.Throttled only
+// counts calls that actually had to wait, not every cache miss.
+func (g *Gate) Check(ctx context.Context, registry, repo, tag string) (digest string, useCache bool, err error) {
+	if d, fresh, backoff := g.cache.Lookup(registry, repo, tag); fresh || backoff {
+		g.cached.Add(1)
+		return d, true, nil
+	}
+
+	reservation := g.limiterFor(registry).Reserve()
+	if !reservation.OK() {
+		return "", false, fmt.Errorf("registry rate limit burst exceeded for %s", registry)
+	}
+
+	if delay := reservation.Delay(); delay > 0 {
+		g.throttled.Add(1)
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			reservation.Cancel()
+			return "", false, ctx.Err()
+		}
+	}
+
+	return "", false, nil
+}
+
+// Observe records the outcome of a request that Check told the caller to
+// perform itself. A positive retryAfter records a 429 backoff window
+// instead of a digest.
+func (g *Gate) Observe(registry, repo, tag, digest string, retryAfter time.Duration) {
+	if retryAfter > 0 {
+		g.cache.StoreRateLimited(registry, repo, tag, retryAfter)
+		return
+	}
+	g.cache.Store(registry, repo, tag, digest, g.cfg.For(registry).CacheTTL)
+}
+
+// Stats returns the current throttled/cached counts for the metrics API.
+func (g *Gate) Stats() Stats {
+	return Stats{Throttled: g.throttled.Load(), Cached: g.cached.Load()}
+}
+
+func (g *Gate) limiterFor(registry string) *rate.Limiter {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if l, ok := g.limiters[registry]; ok {
+		return l
+	}
+
+	limits := g.cfg.For(registry)
+	limit := rate.Limit(limits.RateLimit)
+	if limits.RateLimit <= 0 {
+		// A non-positive rate means no limit was configured for this
+		// registry, rather than "block everything".
+		limit = rate.Inf
+	}
+	l := rate.NewLimiter(limit, limits.Burst)
+	g.limiters[registry] = l
+	return l
+}
@@ -0,0 +1,90 @@
+package throttle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCacheLookupMissThenHit(t *testing.T) {
+	c := NewCache()
+
+	if _, fresh, backoff := c.Lookup("docker.io", "library/nginx", "latest"); fresh || backoff {
+		t.Fatal("expected a miss on an empty cache")
+	}
+
+	c.Store("docker.io", "library/nginx", "latest", "sha256:abc", time.Minute)
+
+	digest, fresh, backoff := c.Lookup("docker.io", "library/nginx", "latest")
+	if !fresh || backoff {
+		t.Fatal("expected a fresh cache hit")
+	}
+	if digest != "sha256:abc" {
+		t.Errorf("expected sha256:abc, got %q", digest)
+	}
+}
+
+func TestCacheEntryExpires(t *testing.T) {
+	c := NewCache()
+	c.Store("docker.io", "library/nginx", "latest", "sha256:abc", -time.Minute)
+
+	if _, fresh, _ := c.Lookup("docker.io", "library/nginx", "latest"); fresh {
+		t.Fatal("expected the entry to have expired")
+	}
+}
+
+func TestCacheRateLimitedBackoff(t *testing.T) {
+	c := NewCache()
+	c.StoreRateLimited("docker.io", "library/nginx", "latest", time.Minute)
+
+	_, fresh, backoff := c.Lookup("docker.io", "library/nginx", "latest")
+	if fresh {
+		t.Error("a rate-limited entry shouldn't also report a fresh digest")
+	}
+	if !backoff {
+		t.Error("expected to still be within the backoff window")
+	}
+}
+
+func TestConfigForFallsBackToDefault(t *testing.T) {
+	cfg := Config{
+		Default:   Limits{RateLimit: 1, Burst: 1},
+		Overrides: map[string]Limits{"docker.io": {RateLimit: 5, Burst: 10}},
+	}
+
+	if got := cfg.For("docker.io"); got.RateLimit != 5 {
+		t.Errorf("expected the docker.io override, got %+v", got)
+	}
+	if got := cfg.For("quay.io"); got.RateLimit != 1 {
+		t.Errorf("expected the default for an unconfigured registry, got %+v", got)
+	}
+}
+
+func TestLoadConfigParsesOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry-throttle.json")
+	contents := `{"docker.io": {"RateLimit": 2, "Burst": 4, "CacheTTL": "5m"}}`
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := LoadConfig(path, Limits{RateLimit: 1, Burst: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := cfg.For("docker.io")
+	if got.RateLimit != 2 || got.Burst != 4 || got.CacheTTL != 5*time.Minute {
+		t.Errorf("unexpected override: %+v", got)
+	}
+}
+
+func TestLoadConfigEmptyPath(t *testing.T) {
+	cfg, err := LoadConfig("", Limits{RateLimit: 1, Burst: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Overrides) != 0 {
+		t.Errorf("expected no overrides, got %+v", cfg.Overrides)
+	}
+}
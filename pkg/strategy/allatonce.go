@@ -0,0 +1,23 @@
+package strategy
+
+import (
+	"github.com/beatkind/watchtower/pkg/container"
+	t "github.com/beatkind/watchtower/pkg/types"
+)
+
+// allAtOnce is watchtower's historical behaviour: every stale container is
+// updated in a single batch with no health gating between containers.
+type allAtOnce struct{}
+
+func (allAtOnce) Name() string { return "all-at-once" }
+
+func (allAtOnce) Plan(containers []container.Container) []Batch {
+	if len(containers) == 0 {
+		return nil
+	}
+	return []Batch{{Name: "all", Containers: containers}}
+}
+
+func (allAtOnce) Execute(batch Batch, client container.Client, params t.UpdateParams) BatchResult {
+	return executeContainers(batch, client, params)
+}
@@ -0,0 +1,99 @@
+package strategy
+
+import (
+	"fmt"
+
+	"github.com/beatkind/watchtower/pkg/container"
+	t "github.com/beatkind/watchtower/pkg/types"
+)
+
+// blueGreen renames the running container out of the way, starts its
+// replacement (built from the latest image) under the original name, and
+// only removes the renamed original once the replacement is confirmed
+// healthy. If the replacement never comes up healthy, it's torn down and
+// the original is renamed back and left running: blue-green's whole point
+// is that a bad image never takes the service down while it's being
+// replaced.
+type blueGreen struct{}
+
+func (blueGreen) Name() string { return "blue-green" }
+
+func (blueGreen) Plan(containers []container.Container) []Batch {
+	batches := make([]Batch, 0, len(containers))
+	for _, c := range containers {
+		batches = append(batches, Batch{
+			Name:       fmt.Sprintf("blue-green:%s", c.Name()),
+			Containers: []container.Container{c},
+		})
+	}
+	return batches
+}
+
+func (blueGreen) Execute(batch Batch, client container.Client, params t.UpdateParams) BatchResult {
+	result := BatchResult{Batch: batch}
+	for _, old := range batch.Containers {
+		if err := rollOutBlueGreen(client, old, params); err != nil {
+			result.Failed = append(result.Failed, old)
+			result.Err = err
+			continue
+		}
+		result.Updated = append(result.Updated, old)
+	}
+	return result
+}
+
+// rollOutBlueGreen performs the rename-start-verify-remove sequence for a
+// single container, restoring the original on any failure.
+func rollOutBlueGreen(client container.Client, old container.Container, params t.UpdateParams) error {
+	name := old.Name()
+	standbyName := name + "_watchtower_old"
+
+	if err := client.RenameContainer(old, standbyName); err != nil {
+		return fmt.Errorf("renaming %s out of the way: %w", name, err)
+	}
+
+	replacement, err := client.StartContainer(old)
+	if err != nil {
+		// The rename succeeded but the replacement never came up: put the
+		// original back under its real name rather than leave the service
+		// down.
+		_ = client.RenameContainer(old, name)
+		return fmt.Errorf("starting replacement for %s: %w", name, err)
+	}
+
+	if err := verifyHealthy(client, replacement, params); err != nil {
+		// old is still the known-good container, just running under
+		// standbyName; replacement - the one that actually failed its
+		// health check - is the one holding name and needs to be the one
+		// stopped.
+		if bad, getErr := client.GetContainer(replacement); getErr == nil {
+			_ = client.StopContainer(bad, params.Timeout)
+		}
+		_ = client.RenameContainer(old, name)
+		return fmt.Errorf("replacement for %s failed its health check, rolled back: %w", name, err)
+	}
+
+	if err := client.StopContainer(old, params.Timeout); err != nil {
+		return fmt.Errorf("stopping original %s after a healthy rollout: %w", name, err)
+	}
+
+	return nil
+}
+
+// verifyHealthy gates the rollout on the container's post-update lifecycle
+// hook when lifecycle hooks are enabled, the same health signal a rolling
+// restart already respects.
+func verifyHealthy(client container.Client, id t.ContainerID, params t.UpdateParams) error {
+	if !params.LifecycleHooks {
+		return nil
+	}
+
+	unhealthy, err := client.ExecuteCommand(id, "", int(params.Timeout.Seconds()))
+	if err != nil {
+		return err
+	}
+	if unhealthy {
+		return fmt.Errorf("post-update lifecycle hook reported the container unhealthy")
+	}
+	return nil
+}
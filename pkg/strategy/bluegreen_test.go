@@ -0,0 +1,123 @@
+package strategy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/beatkind/watchtower/pkg/container"
+	wt "github.com/beatkind/watchtower/pkg/types"
+)
+
+type fakeContainer struct {
+	name string
+}
+
+func (f fakeContainer) Name() string        { return f.name }
+func (f fakeContainer) ImageName() string   { return f.name + ":latest" }
+func (f fakeContainer) ImageID() wt.ImageID { return wt.ImageID("sha256:old") }
+
+// fakeClient is a minimal container.Client double that records the
+// rename/start/stop calls a rollout makes, so tests can assert on exactly
+// which container each call targeted.
+type fakeClient struct {
+	renamedTo map[string]string // container name -> new name, last call wins
+	stopped   []string          // container names StopContainer was called with
+	started   []string          // container names StartContainer started successfully
+
+	startErr   error
+	stopErr    error
+	unhealthy  bool
+	executeErr error
+}
+
+func (f *fakeClient) ListContainers(wt.Filter) ([]container.Container, error) { return nil, nil }
+
+func (f *fakeClient) IsContainerStale(container.Container, wt.UpdateParams) (bool, wt.ImageID, error) {
+	return false, "", nil
+}
+
+func (f *fakeClient) StopContainer(c container.Container, _ time.Duration) error {
+	f.stopped = append(f.stopped, c.Name())
+	return f.stopErr
+}
+
+func (f *fakeClient) StartContainer(c container.Container) (wt.ContainerID, error) {
+	if f.startErr != nil {
+		return "", f.startErr
+	}
+	f.started = append(f.started, c.Name())
+	return wt.ContainerID(c.Name() + "-replacement"), nil
+}
+
+func (f *fakeClient) RenameContainer(c container.Container, newName string) error {
+	if f.renamedTo == nil {
+		f.renamedTo = make(map[string]string)
+	}
+	f.renamedTo[c.Name()] = newName
+	return nil
+}
+
+func (f *fakeClient) GetContainer(id wt.ContainerID) (container.Container, error) {
+	return fakeContainer{name: string(id)}, nil
+}
+
+func (f *fakeClient) ExecuteCommand(wt.ContainerID, string, int) (bool, error) {
+	return f.unhealthy, f.executeErr
+}
+
+func TestBlueGreenRollsBackTheReplacementNotTheOriginal(t *testing.T) {
+	old := fakeContainer{name: "web"}
+	client := &fakeClient{unhealthy: true}
+	params := wt.UpdateParams{LifecycleHooks: true}
+
+	result := blueGreen{}.Execute(Batch{Containers: []container.Container{old}}, client, params)
+
+	if len(result.Updated) != 0 || len(result.Failed) != 1 {
+		t.Fatalf("expected the rollout to be recorded as failed, got %+v", result)
+	}
+
+	// The replacement - "web-replacement" per fakeClient.StartContainer -
+	// is the one that failed its health check and must be the one stopped.
+	if len(client.stopped) != 1 || client.stopped[0] != "web-replacement" {
+		t.Errorf("expected the replacement to be stopped, stopped=%v", client.stopped)
+	}
+
+	// The known-good original must end up back under its real name.
+	if got := client.renamedTo["web"]; got != "web" {
+		t.Errorf("expected the original to be renamed back to %q, got %q", "web", got)
+	}
+}
+
+func TestBlueGreenStartFailureRestoresOriginal(t *testing.T) {
+	old := fakeContainer{name: "web"}
+	client := &fakeClient{startErr: errors.New("no such image")}
+	params := wt.UpdateParams{}
+
+	result := blueGreen{}.Execute(Batch{Containers: []container.Container{old}}, client, params)
+
+	if len(result.Failed) != 1 {
+		t.Fatalf("expected the rollout to be recorded as failed, got %+v", result)
+	}
+	if len(client.stopped) != 0 {
+		t.Errorf("nothing should be stopped when the replacement never started, stopped=%v", client.stopped)
+	}
+	if got := client.renamedTo["web"]; got != "web" {
+		t.Errorf("expected the original to be renamed back to %q, got %q", "web", got)
+	}
+}
+
+func TestBlueGreenHealthyRolloutStopsOnlyTheOriginal(t *testing.T) {
+	old := fakeContainer{name: "web"}
+	client := &fakeClient{}
+	params := wt.UpdateParams{LifecycleHooks: true}
+
+	result := blueGreen{}.Execute(Batch{Containers: []container.Container{old}}, client, params)
+
+	if len(result.Updated) != 1 || len(result.Failed) != 0 {
+		t.Fatalf("expected the rollout to succeed, got %+v", result)
+	}
+	if len(client.stopped) != 1 || client.stopped[0] != "web" {
+		t.Errorf("expected only the original to be stopped, stopped=%v", client.stopped)
+	}
+}
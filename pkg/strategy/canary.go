@@ -0,0 +1,40 @@
+package strategy
+
+import (
+	"github.com/beatkind/watchtower/pkg/container"
+	t "github.com/beatkind/watchtower/pkg/types"
+)
+
+// canary updates a subset of matching containers first so a bad image only
+// affects a fraction of the fleet, then rolls out to the remainder once the
+// canary batch comes back healthy.
+type canary struct {
+	// percentage is the share (0-100) of containers updated in the canary
+	// batch. Anything less than one whole container rounds up to one.
+	percentage int
+}
+
+func (c canary) Name() string { return "canary" }
+
+func (c canary) Plan(containers []container.Container) []Batch {
+	if len(containers) == 0 {
+		return nil
+	}
+
+	size := c.percentage * len(containers) / 100
+	if size < 1 {
+		size = 1
+	}
+	if size >= len(containers) {
+		return []Batch{{Name: "canary", Containers: containers}}
+	}
+
+	return []Batch{
+		{Name: "canary", Containers: containers[:size]},
+		{Name: "remainder", Containers: containers[size:]},
+	}
+}
+
+func (c canary) Execute(batch Batch, client container.Client, params t.UpdateParams) BatchResult {
+	return executeContainers(batch, client, params)
+}
@@ -0,0 +1,49 @@
+package strategy
+
+import (
+	"time"
+
+	"github.com/beatkind/watchtower/pkg/container"
+	t "github.com/beatkind/watchtower/pkg/types"
+)
+
+// staged groups containers by the value of a label (e.g. a deployment ring
+// such as "canary", "staging" or "production") and rolls out one label group
+// at a time, waiting between stages so operators have a window to catch a
+// bad release before it reaches the next group.
+type staged struct {
+	label string
+	wait  time.Duration
+}
+
+func (s staged) Name() string { return "staged" }
+
+func (s staged) Plan(containers []container.Container) []Batch {
+	if s.label == "" {
+		return allAtOnce{}.Plan(containers)
+	}
+
+	var order []string
+	groups := make(map[string][]container.Container)
+	for _, c := range containers {
+		stage := c.ContainerInfo().Config.Labels[s.label]
+		if _, seen := groups[stage]; !seen {
+			order = append(order, stage)
+		}
+		groups[stage] = append(groups[stage], c)
+	}
+
+	batches := make([]Batch, 0, len(order))
+	for _, stage := range order {
+		batches = append(batches, Batch{Name: "stage:" + stage, Containers: groups[stage]})
+	}
+	return batches
+}
+
+func (s staged) Execute(batch Batch, client container.Client, params t.UpdateParams) BatchResult {
+	result := executeContainers(batch, client, params)
+	if s.wait > 0 {
+		time.Sleep(s.wait)
+	}
+	return result
+}
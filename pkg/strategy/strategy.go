@@ -0,0 +1,106 @@
+// Package strategy provides pluggable update rollout strategies for
+// watchtower. A Strategy decides how the set of stale containers is split
+// into batches and how each batch is rolled out, letting an update trade
+// off blast radius against rollout speed instead of always restarting every
+// stale container at once.
+package strategy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/beatkind/watchtower/pkg/container"
+	t "github.com/beatkind/watchtower/pkg/types"
+)
+
+// Batch is a group of containers that should be updated together before the
+// next batch is considered.
+type Batch struct {
+	// Name identifies the batch for logging/notifications, e.g. "canary" or
+	// "stage:blue".
+	Name       string
+	Containers []container.Container
+}
+
+// BatchResult reports the outcome of executing a single Batch.
+type BatchResult struct {
+	Batch   Batch
+	Updated []container.Container
+	Failed  []container.Container
+	Err     error
+}
+
+// Healthy reports whether every container in the batch updated without
+// error. Callers should stop rolling out further batches when this is false.
+func (r BatchResult) Healthy() bool {
+	return r.Err == nil && len(r.Failed) == 0
+}
+
+// Strategy decides how a set of stale containers is split into batches and
+// how each batch is rolled out.
+type Strategy interface {
+	// Name identifies the strategy, matching the --update-strategy flag.
+	Name() string
+	// Plan partitions containers into the batches that should be executed in
+	// order. Implementations may return a single batch for an all-at-once
+	// rollout, or several for a staged/canary rollout.
+	Plan(containers []container.Container) []Batch
+	// Execute updates a single batch and reports its outcome. Callers are
+	// expected to consult BatchResult.Healthy before proceeding to the next
+	// batch returned by Plan.
+	Execute(batch Batch, client container.Client, params t.UpdateParams) BatchResult
+}
+
+// Options configures the strategies returned by New.
+type Options struct {
+	// CanaryPercentage is the share (0-100) of matching containers updated in
+	// the first canary batch.
+	CanaryPercentage int
+	// StageLabel groups containers into stages by the value of this label,
+	// e.g. com.centurylinklabs.watchtower.stage.
+	StageLabel string
+	// StageWait is how long to wait after a stage's batch completes, before
+	// starting the next stage.
+	StageWait time.Duration
+}
+
+// New returns the Strategy registered under name, or an error if name isn't
+// recognised. The empty string selects the default all-at-once strategy,
+// preserving watchtower's historical behaviour.
+func New(name string, opts Options) (Strategy, error) {
+	switch name {
+	case "", "all-at-once":
+		return allAtOnce{}, nil
+	case "canary":
+		return canary{percentage: opts.CanaryPercentage}, nil
+	case "blue-green":
+		return blueGreen{}, nil
+	case "staged":
+		return staged{label: opts.StageLabel, wait: opts.StageWait}, nil
+	default:
+		return nil, fmt.Errorf("unknown update strategy %q", name)
+	}
+}
+
+// executeContainers performs the stop/start cycle for every container in a
+// batch and collects the per-container outcome. It runs no lifecycle hooks
+// and does not check the restarted container's health; a container counts
+// as updated once StartContainer succeeds. It is shared by strategies that
+// don't need custom per-batch sequencing.
+func executeContainers(batch Batch, client container.Client, params t.UpdateParams) BatchResult {
+	result := BatchResult{Batch: batch}
+	for _, c := range batch.Containers {
+		if err := client.StopContainer(c, params.Timeout); err != nil {
+			result.Failed = append(result.Failed, c)
+			result.Err = err
+			continue
+		}
+		if _, err := client.StartContainer(c); err != nil {
+			result.Failed = append(result.Failed, c)
+			result.Err = err
+			continue
+		}
+		result.Updated = append(result.Updated, c)
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package strategy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/beatkind/watchtower/pkg/container"
+	wt "github.com/beatkind/watchtower/pkg/types"
+)
+
+func TestNewDefaultsToAllAtOnce(t *testing.T) {
+	s, err := New("", Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Name() != "all-at-once" {
+		t.Errorf("expected all-at-once, got %q", s.Name())
+	}
+}
+
+func TestNewUnknownStrategy(t *testing.T) {
+	if _, err := New("bogus", Options{}); err == nil {
+		t.Error("expected an error for an unknown strategy")
+	}
+}
+
+func TestNewKnownStrategies(t *testing.T) {
+	for _, name := range []string{"all-at-once", "canary", "blue-green", "staged"} {
+		s, err := New(name, Options{})
+		if err != nil {
+			t.Fatalf("New(%q): unexpected error: %v", name, err)
+		}
+		if s.Name() != name {
+			t.Errorf("New(%q): got name %q", name, s.Name())
+		}
+	}
+}
+
+func TestCanaryPlanRoundsUpToOneContainer(t *testing.T) {
+	s, err := New("canary", Options{CanaryPercentage: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	batches := s.Plan(make([]container.Container, 10))
+	if len(batches) != 2 {
+		t.Fatalf("expected 2 batches, got %d", len(batches))
+	}
+	if len(batches[0].Containers) != 1 {
+		t.Errorf("expected a single-container canary batch, got %d", len(batches[0].Containers))
+	}
+	if len(batches[1].Containers) != 9 {
+		t.Errorf("expected 9 containers in the remainder batch, got %d", len(batches[1].Containers))
+	}
+}
+
+func TestAllAtOncePlansASingleBatch(t *testing.T) {
+	containers := []container.Container{fakeContainer{name: "web"}, fakeContainer{name: "db"}}
+
+	batches := allAtOnce{}.Plan(containers)
+
+	if len(batches) != 1 || len(batches[0].Containers) != 2 {
+		t.Fatalf("expected one batch with both containers, got %+v", batches)
+	}
+}
+
+func TestAllAtOncePlansNoBatchesForNoContainers(t *testing.T) {
+	if batches := (allAtOnce{}).Plan(nil); batches != nil {
+		t.Errorf("expected no batches for no containers, got %+v", batches)
+	}
+}
+
+func TestAllAtOnceExecuteUpdatesEveryContainer(t *testing.T) {
+	containers := []container.Container{fakeContainer{name: "web"}, fakeContainer{name: "db"}}
+	client := &fakeClient{}
+
+	result := allAtOnce{}.Execute(Batch{Name: "all", Containers: containers}, client, wt.UpdateParams{})
+
+	if !result.Healthy() || len(result.Updated) != 2 {
+		t.Fatalf("expected both containers updated, got %+v", result)
+	}
+	if len(client.started) == 0 {
+		t.Errorf("expected containers to be started, client=%+v", client)
+	}
+}
+
+func TestAllAtOnceExecuteFailsOnAStopFailure(t *testing.T) {
+	client := &fakeClient{stopErr: errors.New("container in use")}
+	old := fakeContainer{name: "web"}
+
+	result := allAtOnce{}.Execute(Batch{Name: "all", Containers: []container.Container{old}}, client, wt.UpdateParams{})
+
+	if result.Healthy() || len(result.Failed) != 1 {
+		t.Fatalf("expected the batch to be reported unhealthy, got %+v", result)
+	}
+}
+
+func TestStagedExecuteUpdatesItsBatchWithoutWaiting(t *testing.T) {
+	client := &fakeClient{}
+	old := fakeContainer{name: "web"}
+
+	result := staged{}.Execute(Batch{Name: "stage:canary", Containers: []container.Container{old}}, client, wt.UpdateParams{})
+
+	if !result.Healthy() || len(result.Updated) != 1 {
+		t.Fatalf("expected the stage's container to be updated, got %+v", result)
+	}
+}
+
+func TestStagedExecuteReportsStartFailure(t *testing.T) {
+	client := &fakeClient{startErr: errors.New("no such image")}
+	old := fakeContainer{name: "web"}
+
+	result := staged{}.Execute(Batch{Name: "stage:canary", Containers: []container.Container{old}}, client, wt.UpdateParams{})
+
+	if result.Healthy() || len(result.Failed) != 1 {
+		t.Fatalf("expected the stage's container update to be reported as failed, got %+v", result)
+	}
+}